@@ -2,19 +2,34 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
 	"strconv"
+	"sync/atomic"
+	"time"
 
-	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 )
 
+const (
+	// minChunkedDownloadSize is the smallest file size for which chunked
+	// downloading is worth the extra HEAD round-trip and goroutine overhead.
+	minChunkedDownloadSize = 8 * 1024 * 1024 // 8MiB
+	defaultDownloadChunks  = 4
+)
+
+// DownloadChunks controls how many parallel Range requests chunked downloads use.
+// Set to 1 (or less) to always fall back to the single-stream download path.
+var DownloadChunks = defaultDownloadChunks
+
 func downloadAssetHandler(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -40,11 +55,16 @@ func downloadAssetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	taskID := uuid.New().String()
-	go doAssetDownload(rJSON, downloadData, taskID)
+	// A single asset is just a one-entry batch; routing it through
+	// startBatchDownload keeps one download engine (queueing, concurrency
+	// limiting) behind both this endpoint and the batch one.
+	taskIDs, _ := startBatchDownload(
+		BatchDownloadRequest{AppID: downloadData.AppID, Assets: []DownloadData{downloadData}},
+		[]interface{}{rJSON},
+	)
 
 	// Response to add-on
-	resData := map[string]string{"task_id": taskID}
+	resData := map[string]string{"task_id": taskIDs[0]}
 	responseJSON, err := json.Marshal(resData)
 	if err != nil {
 		http.Error(w, "Error converting to JSON: "+err.Error(), http.StatusInternalServerError)
@@ -55,15 +75,22 @@ func downloadAssetHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(responseJSON)
 }
 
-func doAssetDownload(origJSON map[string]interface{}, data DownloadData, taskID string) {
+func doAssetDownload(origJSON map[string]interface{}, data DownloadData, taskID string, batchCtx context.Context) {
 	TasksMux.Lock()
 	task := NewTask(origJSON, data.AppID, taskID, "asset_download")
 	task.Message = "Getting download URL"
 	Tasks[task.AppID][taskID] = task
 	TasksMux.Unlock()
 
+	// Derive this task's context from the batch's, so cancelBatch actually
+	// stops an asset download that's already running past the semaphore, not
+	// just ones still queued behind it.
+	var cancelTaskCtx context.CancelFunc
+	task.Ctx, cancelTaskCtx = context.WithCancel(batchCtx)
+	defer cancelTaskCtx()
+
 	// GET URL FOR BLEND FILE WITH CORRECT RESOLUTION
-	canDownload, downloadURL, err := GetDownloadURL(data)
+	canDownload, downloadURL, sha256Expected, err := GetDownloadURL(data)
 	if err != nil {
 		TaskErrorCh <- &TaskError{
 			AppID:  data.AppID,
@@ -101,9 +128,17 @@ func doAssetDownload(origJSON map[string]interface{}, data DownloadData, taskID
 		Progress: 0,
 		Message:  "Getting filepaths",
 	}
-	downloadFilePaths := GetDownloadFilepaths(data, fileName)
+	downloadTargets, err := GetDownloadFilepaths(data, fileName)
+	if err != nil {
+		TaskErrorCh <- &TaskError{
+			AppID:  data.AppID,
+			TaskID: taskID,
+			Error:  err,
+		}
+		return
+	}
 
-	// CHECK IF FILE EXISTS ON HARD DRIVE
+	// CHECK IF FILE EXISTS ON HARD DRIVE (OR OTHER STORAGE BACKEND)
 	TaskProgressUpdateCh <- &TaskProgressUpdate{
 		AppID:    data.AppID,
 		TaskID:   taskID,
@@ -111,21 +146,22 @@ func doAssetDownload(origJSON map[string]interface{}, data DownloadData, taskID
 		Message:  "Checking files on disk",
 	}
 	existingFiles := 0
-	for _, filePath := range downloadFilePaths {
-		exists, info, err := FileExists(filePath)
+	for _, target := range downloadTargets {
+		exists, _, err := target.Backend.Exists(target.Key)
 		if err != nil {
-			if info.IsDir() {
-				fmt.Println("Deleting directory:", filePath)
-				err := os.RemoveAll(filePath)
-				if err != nil {
-					fmt.Println("Error deleting directory:", err)
-				}
-			} else {
-				fmt.Println("Error checking if file exists:", err)
-			}
+			fmt.Println("Error checking if file exists:", err)
 			continue
 		}
 		if exists {
+			// Cheap re-hash against the stored sidecar is only worth it for local
+			// disk; remote backends are trusted on existence alone.
+			if _, isLocal := target.Backend.(localFSBackend); isLocal && !fileOnDiskIsValid(target.Key, sha256Expected) {
+				log.Println("existing file failed integrity re-verification, will re-download:", target.Key)
+				if err := target.Backend.Delete(target.Key); err != nil {
+					log.Println("Error deleting corrupt file:", err)
+				}
+				continue
+			}
 			existingFiles++
 		}
 	}
@@ -135,16 +171,15 @@ func doAssetDownload(origJSON map[string]interface{}, data DownloadData, taskID
 		action = "download"
 	} else if existingFiles == 2 { // Both files exist -> skip download
 		action = "place"
-	} else if existingFiles == 1 && len(downloadFilePaths) == 2 { // One file exists, but there are two download paths -> sync the missing file
+	} else if existingFiles == 1 && len(downloadTargets) == 2 { // One file exists, but there are two download paths -> sync the missing file
 		// TODO: sync the missing file
 		action = "sync"
-	} else if existingFiles == 1 && len(downloadFilePaths) == 1 { // One file exists, and there is only one download path -> skip download
+	} else if existingFiles == 1 && len(downloadTargets) == 1 { // One file exists, and there is only one download path -> skip download
 		action = "place"
 	} else { // Something unexpected happened -> delete and download
 		log.Println("Unexpected number of existing files:", existingFiles)
-		for _, file := range downloadFilePaths {
-			err := DeleteFile(file)
-			if err != nil {
+		for _, target := range downloadTargets {
+			if err := target.Backend.Delete(target.Key); err != nil {
 				log.Println("Error deleting file:", err)
 			}
 		}
@@ -152,14 +187,28 @@ func doAssetDownload(origJSON map[string]interface{}, data DownloadData, taskID
 
 	// START DOWNLOAD IF NEEDED
 	if action == "download" {
-		fp := downloadFilePaths[0]
-		err = downloadAsset(downloadURL, fp, data, taskID, task.Ctx)
+		target := downloadTargets[0]
+		var resumeFrom int64
+		if _, isLocal := target.Backend.(localFSBackend); isLocal {
+			if rf, ok := tryResumeDownload(task.Ctx, downloadURL, target.Key); ok {
+				action = "resume"
+				resumeFrom = rf
+				TaskProgressUpdateCh <- &TaskProgressUpdate{
+					AppID:    data.AppID,
+					TaskID:   taskID,
+					Progress: 0,
+					Message:  "Resuming download",
+				}
+			}
+		}
+		err = downloadAsset(downloadURL, target, data, taskID, task.Ctx, resumeFrom, sha256Expected)
 		if err != nil {
-			e := fmt.Errorf("error downloading asset: %v", err)
+			e := fmt.Errorf("error downloading asset: %w", err)
 			TaskErrorCh <- &TaskError{
 				AppID:  data.AppID,
 				TaskID: taskID,
 				Error:  e,
+				Code:   errorCode(err),
 			}
 			return
 		}
@@ -171,7 +220,11 @@ func doAssetDownload(origJSON map[string]interface{}, data DownloadData, taskID
 		// TODO: UNPACK FILE
 	}
 
-	result := map[string]interface{}{"file_paths": downloadFilePaths}
+	filePaths := make([]string, len(downloadTargets))
+	for i, target := range downloadTargets {
+		filePaths[i] = target.Key
+	}
+	result := map[string]interface{}{"file_paths": filePaths}
 	TaskFinishCh <- &TaskFinish{
 		AppID:   data.AppID,
 		TaskID:  taskID,
@@ -180,22 +233,543 @@ func doAssetDownload(origJSON map[string]interface{}, data DownloadData, taskID
 	}
 }
 
-func downloadAsset(url, filePath string, data DownloadData, taskID string, ctx context.Context) error {
-	file, err := os.Create(filePath)
+// downloadSidecar is the JSON metadata BlenderKit writes next to a `.part`
+// file so an interrupted download can be resumed later.
+type downloadSidecar struct {
+	DownloadURL    string    `json:"download_url"`
+	ETag           string    `json:"etag"`
+	ContentLength  int64     `json:"content_length"`
+	BytesWritten   int64     `json:"bytes_written"`
+	SHA256Expected string    `json:"sha256_expected,omitempty"`
+	StartedAt      time.Time `json:"started_at"`
+}
+
+func partFilePath(filePath string) string    { return filePath + ".part" }
+func sidecarFilePath(filePath string) string { return partFilePath(filePath) + ".json" }
+
+func writeSidecar(filePath string, sidecar downloadSidecar) error {
+	b, err := json.Marshal(sidecar)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sidecarFilePath(filePath), b, 0644)
+}
+
+func readSidecar(filePath string) (downloadSidecar, error) {
+	var sidecar downloadSidecar
+	b, err := os.ReadFile(sidecarFilePath(filePath))
+	if err != nil {
+		return sidecar, err
+	}
+	err = json.Unmarshal(b, &sidecar)
+	return sidecar, err
+}
+
+func removeResumeState(filePath string) {
+	os.Remove(partFilePath(filePath))
+	os.Remove(sidecarFilePath(filePath))
+}
+
+// integrityError marks a download failure caused by a sha256 mismatch, so
+// the add-on can tell it apart from a plain network/IO error and retry.
+type integrityError struct {
+	Code string
+	err  error
+}
+
+func (e *integrityError) Error() string { return e.err.Error() }
+func (e *integrityError) Unwrap() error { return e.err }
+
+func newIntegrityFailure(format string, args ...interface{}) error {
+	return &integrityError{Code: "integrity_failure", err: fmt.Errorf(format, args...)}
+}
+
+// errorCode extracts an *integrityError's Code from err's chain, if present,
+// so it can be surfaced on TaskError for the add-on to react to (e.g. retry
+// on "integrity_failure" instead of treating every download error the same).
+func errorCode(err error) string {
+	var integrityErr *integrityError
+	if errors.As(err, &integrityErr) {
+		return integrityErr.Code
+	}
+	return ""
+}
+
+// metaSidecar is the JSON metadata written alongside a successfully verified
+// download so a later run can skip re-hashing an unchanged file.
+type metaSidecar struct {
+	SHA256     string    `json:"sha256"`
+	Size       int64     `json:"size"`
+	VerifiedAt time.Time `json:"verified_at"`
+	Resolution string    `json:"resolution"`
+	SourceURL  string    `json:"source_url"`
+}
+
+func metaSidecarPath(filePath string) string { return filePath + ".meta.json" }
+
+func writeMetaSidecar(filePath string, meta metaSidecar) error {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metaSidecarPath(filePath), b, 0644)
+}
+
+func readMetaSidecar(filePath string) (metaSidecar, error) {
+	var meta metaSidecar
+	b, err := os.ReadFile(metaSidecarPath(filePath))
+	if err != nil {
+		return meta, err
+	}
+	err = json.Unmarshal(b, &meta)
+	return meta, err
+}
+
+func hashFileSHA256(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyDownloadIntegrity hashes the just-downloaded file at filePath and
+// compares it against sha256Expected (skipped if the server didn't supply a
+// hash). On success it writes/refreshes the `.meta.json` sidecar so a future
+// existence check can skip re-hashing; on mismatch it deletes the file and
+// returns an *integrityError.
+func verifyDownloadIntegrity(filePath, sha256Expected, resolution, sourceURL string) error {
+	actualHash, err := hashFileSHA256(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to hash downloaded file: %v", err)
+	}
+
+	if sha256Expected != "" && actualHash != sha256Expected {
+		e := DeleteFile(filePath)
+		os.Remove(metaSidecarPath(filePath))
+		if e != nil {
+			return newIntegrityFailure("sha256 mismatch (expected %s, got %s), failed to delete file: %v", sha256Expected, actualHash, e)
+		}
+		return newIntegrityFailure("sha256 mismatch: expected %s, got %s", sha256Expected, actualHash)
+	}
+
+	var size int64
+	if info, err := os.Stat(filePath); err == nil {
+		size = info.Size()
+	}
+	meta := metaSidecar{
+		SHA256:     actualHash,
+		Size:       size,
+		VerifiedAt: time.Now(),
+		Resolution: resolution,
+		SourceURL:  sourceURL,
+	}
+	if err := writeMetaSidecar(filePath, meta); err != nil {
+		log.Println("failed to write integrity sidecar:", err)
+	}
+	return nil
+}
+
+// fileOnDiskIsValid is used by doAssetDownload's existence check: it trusts a
+// fresh-looking `.meta.json` sidecar without re-hashing (fast path), and falls
+// back to re-verifying against sha256Expected when the sidecar is missing,
+// unreadable, or doesn't match what the server currently reports.
+func fileOnDiskIsValid(filePath, sha256Expected string) bool {
+	if sha256Expected == "" {
+		return true // server gave us nothing to check against, trust the file
+	}
+
+	if meta, err := readMetaSidecar(filePath); err == nil && meta.SHA256 == sha256Expected {
+		return true
+	}
+
+	actualHash, err := hashFileSHA256(filePath)
+	if err != nil {
+		log.Println("failed to hash existing file for integrity check:", err)
+		return false
+	}
+	return actualHash == sha256Expected
+}
+
+// tryResumeDownload looks for a `.part` + sidecar left over from a previous,
+// interrupted attempt at filePath. If the sidecar's ETag still matches a fresh
+// HEAD on downloadURL, the download can resume from sidecar.BytesWritten;
+// otherwise any stale resume state is discarded.
+func tryResumeDownload(ctx context.Context, downloadURL, filePath string) (int64, bool) {
+	if _, err := os.Stat(partFilePath(filePath)); err != nil {
+		return 0, false
+	}
+	sidecar, err := readSidecar(filePath)
+	if err != nil || sidecar.BytesWritten <= 0 {
+		removeResumeState(filePath)
+		return 0, false
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", downloadURL, nil)
+	if err != nil {
+		return 0, false
+	}
+	req.Header = getHeaders("", *SystemID)
+	resp, err := ClientDownloads.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") != sidecar.ETag || sidecar.ETag == "" {
+		removeResumeState(filePath)
+		return 0, false
+	}
+
+	return sidecar.BytesWritten, true
+}
+
+// downloadAsset picks the fastest viable download strategy for the file at url:
+// resuming from resumeFrom when doAssetDownload found a valid `.part` + sidecar,
+// chunked parallel Range requests when the server supports them and the file is
+// large enough to benefit, or a single-stream GET otherwise.
+func downloadAsset(url string, target downloadTarget, data DownloadData, taskID string, ctx context.Context, resumeFrom int64, sha256Expected string) error {
+	// Range-based chunking, resume, and the local sidecar cache all assume
+	// random-access writes to a real file, so they're only available for the
+	// local disk backend; any other backend gets a plain streamed copy.
+	if _, isLocal := target.Backend.(localFSBackend); !isLocal {
+		return downloadAssetToBackend(url, target, data, taskID, ctx, sha256Expected)
+	}
+	filePath := target.Key
+
+	var err error
+	switch {
+	case resumeFrom > 0:
+		err = downloadAssetSingle(url, filePath, data, taskID, ctx, resumeFrom)
+	case DownloadChunks > 1:
+		err = func() error {
+			acceptsRanges, fileSize, rangeErr := checkRangeSupport(ctx, url)
+			if rangeErr != nil {
+				log.Println("range support check failed, falling back to single stream download:", rangeErr)
+			} else if acceptsRanges && fileSize >= minChunkedDownloadSize {
+				if chunkedErr := downloadAssetChunked(url, filePath, data, taskID, ctx, fileSize, DownloadChunks); chunkedErr == nil {
+					return nil
+				} else {
+					log.Println("chunked download failed, falling back to single stream download:", chunkedErr)
+				}
+			}
+			return downloadAssetSingle(url, filePath, data, taskID, ctx, 0)
+		}()
+	default:
+		err = downloadAssetSingle(url, filePath, data, taskID, ctx, 0)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Downloaded bytes can arrive out of order (chunked) or be appended to an
+	// existing prefix (resumed), so integrity is verified in one pass over the
+	// finished file rather than streamed through each write path individually.
+	return verifyDownloadIntegrity(filePath, sha256Expected, data.Resolution, url)
+}
+
+// downloadAssetToBackend is the storage-agnostic download path used for any
+// backend other than local disk: a single sequential GET streamed straight
+// into target.Backend.Writer, hashed as it goes so integrity is still checked
+// even though there's no local file to re-hash afterwards.
+func downloadAssetToBackend(url string, target downloadTarget, data DownloadData, taskID string, ctx context.Context, sha256Expected string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = getHeaders("", *SystemID)
+
+	resp, err := ClientDownloads.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned non-OK status: %d", resp.StatusCode)
+	}
+
+	fileSize, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Content-Length header is missing or invalid")
+	}
+
+	writer, err := target.Backend.Writer(target.Key)
+	if err != nil {
+		return err
+	}
+
+	hasher := sha256.New()
+	out := io.MultiWriter(writer, hasher)
+	sizeInMB := float64(fileSize) / 1024 / 1024
+	var downloaded int64
+	buffer := make([]byte, 32*1024) // 32KB buffer
+	for {
+		select {
+		case <-ctx.Done():
+			writer.Close()
+			target.Backend.Delete(target.Key)
+			return ctx.Err()
+		default:
+		}
+		n, readErr := resp.Body.Read(buffer)
+		if n > 0 {
+			if _, writeErr := out.Write(buffer[:n]); writeErr != nil {
+				writer.Close()
+				target.Backend.Delete(target.Key)
+				return writeErr
+			}
+			downloaded += int64(n)
+			progress := int(100 * downloaded / fileSize)
+			var downloadMessage string
+			if sizeInMB < 1 {
+				downloadMessage = fmt.Sprintf("Downloading %dkB (%d%%)", int(sizeInMB*1024), progress)
+			} else {
+				downloadMessage = fmt.Sprintf("Downloading %.1fMB (%d%%)", sizeInMB, progress)
+			}
+			TaskProgressUpdateCh <- &TaskProgressUpdate{
+				AppID:    data.AppID,
+				TaskID:   taskID,
+				Progress: progress,
+				Message:  downloadMessage,
+			}
+		}
+		if readErr != nil {
+			if readErr != io.EOF {
+				writer.Close()
+				target.Backend.Delete(target.Key)
+				return readErr
+			}
+			break
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		target.Backend.Delete(target.Key)
+		return fmt.Errorf("failed to finalize upload: %v", err)
+	}
+
+	actualHash := hex.EncodeToString(hasher.Sum(nil))
+	if sha256Expected != "" && actualHash != sha256Expected {
+		e := target.Backend.Delete(target.Key)
+		if e != nil {
+			return newIntegrityFailure("sha256 mismatch (expected %s, got %s), failed to delete object: %v", sha256Expected, actualHash, e)
+		}
+		return newIntegrityFailure("sha256 mismatch: expected %s, got %s", sha256Expected, actualHash)
+	}
+	return nil
+}
+
+// checkRangeSupport issues a HEAD request to learn the file size and whether
+// the server honors Range requests for it.
+func checkRangeSupport(ctx context.Context, url string) (bool, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return false, 0, err
+	}
+	req.Header = getHeaders("", *SystemID)
+
+	resp, err := ClientDownloads.Do(req)
+	if err != nil {
+		return false, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, 0, fmt.Errorf("HEAD request returned non-OK status: %d", resp.StatusCode)
+	}
+
+	fileSize, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("Content-Length header is missing or invalid")
+	}
+
+	return resp.Header.Get("Accept-Ranges") == "bytes", fileSize, nil
+}
+
+// downloadAssetChunked splits fileSize across numChunks workers that each download
+// one byte range in parallel, writing directly into their slice of a `.part`
+// file that's renamed to filePath once every chunk has landed - the same
+// convention downloadAssetSingle uses, so a hard kill mid-download never
+// leaves a truncated file sitting at the real final path.
+func downloadAssetChunked(url, filePath string, data DownloadData, taskID string, ctx context.Context, fileSize int64, numChunks int) error {
+	partPath := partFilePath(filePath)
+	file, err := os.Create(partPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := file.Truncate(fileSize); err != nil {
+		e := DeleteFile(partPath)
+		if e != nil {
+			return fmt.Errorf("%v, failed to delete file: %v", err, e)
+		}
+		return err
+	}
+
+	sizeInMB := float64(fileSize) / 1024 / 1024
+	var downloaded int64
+	chunkSize := fileSize / int64(numChunks)
+	g, gctx := errgroup.WithContext(ctx)
+
+	// The ticker watches gctx, not the outer ctx: as soon as any worker fails
+	// (or the outer ctx is cancelled), errgroup cancels gctx and this goroutine
+	// exits instead of spinning forever waiting for a completion that will
+	// never come.
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		ticker := time.NewTicker(300 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-gctx.Done():
+				return
+			case <-ticker.C:
+				d := atomic.LoadInt64(&downloaded)
+				progress := int(100 * d / fileSize)
+				var downloadMessage string
+				if sizeInMB < 1 {
+					downloadMessage = fmt.Sprintf("Downloading %dkB (%d%%)", int(sizeInMB*1024), progress)
+				} else {
+					downloadMessage = fmt.Sprintf("Downloading %.1fMB (%d%%)", sizeInMB, progress)
+				}
+				TaskProgressUpdateCh <- &TaskProgressUpdate{
+					AppID:    data.AppID,
+					TaskID:   taskID,
+					Progress: progress,
+					Message:  downloadMessage,
+				}
+				if d >= fileSize {
+					return
+				}
+			}
+		}
+	}()
+
+	for i := 0; i < numChunks; i++ {
+		start := int64(i) * chunkSize
+		end := start + chunkSize - 1
+		if i == numChunks-1 {
+			end = fileSize - 1
+		}
+		g.Go(func() error {
+			return downloadChunk(gctx, url, file, start, end, &downloaded)
+		})
+	}
+	err = g.Wait()
+	<-progressDone
+
+	if err != nil {
+		e := DeleteFile(partPath)
+		if e != nil {
+			return fmt.Errorf("%v, failed to delete file: %v", err, e)
+		}
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to finalize downloaded file: %v", err)
+	}
+	if err := os.Rename(partPath, filePath); err != nil {
+		return fmt.Errorf("failed to finalize downloaded file: %v", err)
+	}
+	return nil
+}
+
+// downloadChunk downloads the inclusive byte range [start, end] and writes it
+// into file at the matching offset, accumulating its progress into downloaded.
+func downloadChunk(ctx context.Context, url string, file *os.File, start, end int64, downloaded *int64) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = getHeaders("", *SystemID)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	resp, err := ClientDownloads.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("server returned non-partial status for range request: %d", resp.StatusCode)
+	}
+
+	buffer := make([]byte, 32*1024) // 32KB buffer
+	offset := start
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		n, readErr := resp.Body.Read(buffer)
+		if n > 0 {
+			if _, err := file.WriteAt(buffer[:n], offset); err != nil {
+				return err
+			}
+			offset += int64(n)
+			atomic.AddInt64(downloaded, int64(n))
+		}
+		if readErr != nil {
+			if readErr == io.EOF {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
+
+// downloadAssetSingle is the sequential-GET download path, used when the server
+// doesn't support Range requests or the file is too small to chunk. It writes
+// to a `<filePath>.part` file with a JSON sidecar tracking progress; with
+// resumeFrom > 0 it continues that `.part` file from resumeFrom bytes instead
+// of restarting from scratch. Cancellation and read, write or response errors
+// delete the `.part` file, so only a `.part` left behind by a process that
+// stopped mid-download is there to be resumed.
+func downloadAssetSingle(url, filePath string, data DownloadData, taskID string, ctx context.Context, resumeFrom int64) error {
+	partPath := partFilePath(filePath)
+	resuming := resumeFrom > 0
+
+	var file *os.File
+	var err error
+	if resuming {
+		file, err = os.OpenFile(partPath, os.O_WRONLY, 0644)
+	} else {
+		file, err = os.Create(partPath)
+	}
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
+	if resuming {
+		if _, err := file.Seek(resumeFrom, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
 	req.Header = getHeaders("", *SystemID) // download needs no API key in headers
+	if resuming {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", resumeFrom))
+	}
 	resp, err := ClientDownloads.Do(req)
 	if err != nil {
-		e := DeleteFile(filePath)
+		e := DeleteFile(partPath)
 		if e != nil {
 			return fmt.Errorf("request failed: %v, failed to delete file: %v", err, e)
 		}
@@ -203,36 +777,53 @@ func downloadAsset(url, filePath string, data DownloadData, taskID string, ctx c
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	expectedStatus := http.StatusOK
+	if resuming {
+		expectedStatus = http.StatusPartialContent
+	}
+	if resp.StatusCode != expectedStatus {
 		err := fmt.Errorf("server returned non-OK status: %d", resp.StatusCode)
-		e := DeleteFile(filePath)
+		removeResumeState(filePath)
+		e := DeleteFile(partPath)
 		if e != nil {
 			return fmt.Errorf("%v, failed to delete file: %v", err, e)
 		}
 		return err
 	}
 
-	totalLength := resp.Header.Get("Content-Length")
-	if totalLength == "" {
-		e := DeleteFile(filePath)
+	remainingLength := resp.Header.Get("Content-Length")
+	if remainingLength == "" {
+		e := DeleteFile(partPath)
 		if e != nil {
 			return fmt.Errorf("request failed: %v, failed to delete file: %v", err, e)
 		}
 		return fmt.Errorf("Content-Length header is missing")
 	}
 
-	fileSize, err := strconv.ParseInt(totalLength, 10, 64)
+	remaining, err := strconv.ParseInt(remainingLength, 10, 64)
 	if err != nil {
-		e := DeleteFile(filePath)
+		e := DeleteFile(partPath)
 		if e != nil {
 			return fmt.Errorf("length conversion failed: %v, failed to delete file: %v", err, e)
 		}
 		return err
 	}
+	fileSize := resumeFrom + remaining
 
-	// Setup for monitoring progress and cancellation
+	sidecar := downloadSidecar{
+		DownloadURL:   url,
+		ETag:          resp.Header.Get("ETag"),
+		ContentLength: fileSize,
+		BytesWritten:  resumeFrom,
+		StartedAt:     time.Now(),
+	}
+	if err := writeSidecar(filePath, sidecar); err != nil {
+		log.Println("failed to write resume sidecar:", err)
+	}
+
+	// Setup for monitoring progress, sidecar persistence, and cancellation
 	sizeInMB := float64(fileSize) / 1024 / 1024
-	var downloaded int64 = 0
+	downloaded := resumeFrom
 	progress := make(chan int64)
 	go func() {
 		var downloadMessage string
@@ -249,6 +840,10 @@ func downloadAsset(url, filePath string, data DownloadData, taskID string, ctx c
 				Progress: progress,
 				Message:  downloadMessage,
 			}
+			sidecar.BytesWritten = p
+			if err := writeSidecar(filePath, sidecar); err != nil {
+				log.Println("failed to update resume sidecar:", err)
+			}
 		}
 	}()
 
@@ -257,7 +852,7 @@ func downloadAsset(url, filePath string, data DownloadData, taskID string, ctx c
 		select {
 		case <-ctx.Done():
 			close(progress)
-			err = DeleteFile(filePath)
+			err = DeleteFile(partPath)
 			if err != nil {
 				return fmt.Errorf("%v, failed to delete file: %v", ctx.Err(), err)
 			}
@@ -268,7 +863,7 @@ func downloadAsset(url, filePath string, data DownloadData, taskID string, ctx c
 				_, writeErr := file.Write(buffer[:n])
 				if writeErr != nil {
 					close(progress)
-					err = DeleteFile(filePath) // Clean up; ignore error from DeleteFile to focus on writeErr
+					err = DeleteFile(partPath) // Clean up; ignore error from DeleteFile to focus on writeErr
 					if err != nil {
 						return fmt.Errorf("%v, failed to delete file: %v", writeErr, err)
 					}
@@ -280,9 +875,14 @@ func downloadAsset(url, filePath string, data DownloadData, taskID string, ctx c
 			if readErr != nil {
 				close(progress)
 				if readErr == io.EOF {
+					file.Close()
+					if err := os.Rename(partPath, filePath); err != nil {
+						return fmt.Errorf("failed to finalize downloaded file: %v", err)
+					}
+					os.Remove(sidecarFilePath(filePath))
 					return nil // Download completed successfully
 				}
-				err := DeleteFile(filePath) // Clean up; ignore error from DeleteFile to focus on readErr
+				err := DeleteFile(partPath) // Clean up; ignore error from DeleteFile to focus on readErr
 				if err != nil {
 					return fmt.Errorf("%v, failed to delete file: %v", readErr, err)
 				}
@@ -292,24 +892,42 @@ func downloadAsset(url, filePath string, data DownloadData, taskID string, ctx c
 	}
 }
 
-// should return ['/Users/ag/blenderkit_data/models/kitten_0992088b-fb84-4c69-bb6e-426272970c8b/kitten_2K_d5368c9d-092e-4319-afe1-dd765de6da01.blend']
-func GetDownloadFilepaths(data DownloadData, filename string) []string {
-	filePaths := []string{}
+// GetDownloadFilepaths resolves each of data.DownloadDirs (a local path or a
+// backend URI like file:///... or s3://bucket/prefix) to the StorageBackend
+// that serves it and the backend-relative key the asset should be written
+// to/read from, e.g. a local key looks like
+// '/Users/ag/blenderkit_data/models/kitten_0992088b-fb84-4c69-bb6e-426272970c8b/kitten_2K_d5368c9d-092e-4319-afe1-dd765de6da01.blend'.
+func GetDownloadFilepaths(data DownloadData, filename string) ([]downloadTarget, error) {
+	targets := []downloadTarget{}
 	filename = ServerToLocalFilename(filename, data.AssetData.Name)
 	assetFolderName := fmt.Sprintf("%s_%s", Slugify(data.AssetData.Name), data.AssetData.ID)
 	for _, dir := range data.DownloadDirs {
-		assetDirPath := filepath.Join(dir, assetFolderName)
-		if _, err := os.Stat(assetDirPath); os.IsNotExist(err) {
-			os.MkdirAll(assetDirPath, os.ModePerm)
+		backend, dirKey, err := BackendForURI(dir)
+		if err != nil {
+			return nil, err
+		}
+		assetDirKey := backend.Join(dirKey, assetFolderName)
+		fileKey := backend.Join(assetDirKey, filename)
+		// downloadAssetSingle/downloadAssetChunked os.Create straight into
+		// fileKey's directory for local targets rather than going through
+		// Backend.Writer (the only place that otherwise MkdirAll's), so it
+		// has to exist up front.
+		if _, isLocal := backend.(localFSBackend); isLocal {
+			if err := os.MkdirAll(assetDirKey, os.ModePerm); err != nil {
+				return nil, err
+			}
 		}
-		filePath := filepath.Join(assetDirPath, filename)
-		filePaths = append(filePaths, filePath)
+		targets = append(targets, downloadTarget{Backend: backend, Key: fileKey})
 	}
 	// TODO: check on Windows if path is not too long
-	return filePaths
+	return targets, nil
 }
 
-func GetDownloadURL(data DownloadData) (bool, string, error) {
+// GetDownloadURL asks the BlenderKit API for the signed download URL of the
+// resolution file matching data.Resolution, along with the sha256 hash the
+// files API has on record for it (used later for post-download verification;
+// empty if the server didn't supply one).
+func GetDownloadURL(data DownloadData) (bool, string, string, error) {
 	reqData := url.Values{}
 	reqData.Set("scene_uuid", data.SceneID)
 
@@ -317,38 +935,41 @@ func GetDownloadURL(data DownloadData) (bool, string, error) {
 
 	req, err := http.NewRequest("GET", file.DownloadURL, nil)
 	if err != nil {
-		return false, "", err
+		return false, "", "", err
 	}
 	req.Header = getHeaders(data.APIKey, *SystemID)
 	req.URL.RawQuery = reqData.Encode()
 
 	resp, err := ClientAPI.Do(req)
 	if err != nil {
-		return false, "", err
+		return false, "", "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, "", fmt.Errorf("server returned non-OK status: %d", resp.StatusCode)
+		return false, "", "", fmt.Errorf("server returned non-OK status: %d", resp.StatusCode)
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return false, "", err
+		return false, "", "", err
 	}
 
 	var respJSON map[string]interface{}
 	err = json.Unmarshal(bodyBytes, &respJSON)
 	if err != nil {
-		return false, "", err
+		return false, "", "", err
 	}
 
-	url, ok := respJSON["filePath"].(string)
-	if !ok || url == "" {
-		return false, "", fmt.Errorf("filePath is None or invalid")
+	downloadURL, ok := respJSON["filePath"].(string)
+	if !ok || downloadURL == "" {
+		return false, "", "", fmt.Errorf("filePath is None or invalid")
 	}
 
-	return true, url, nil
+	// The signing endpoint only hands back filePath; the sha256 BlenderKit's
+	// files API already has on record for this resolution lives on the
+	// resolved file itself.
+	return true, downloadURL, file.SHA256, nil
 }
 
 func GetResolutionFile(files []File, targetRes string) (File, string) {
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/semaphore"
+)
+
+// MaxParallelDownloads bounds how many asset downloads run at once across all
+// batches, so dragging a whole collection into Blender doesn't saturate the
+// CDN or disk with dozens of simultaneous downloads.
+var MaxParallelDownloads int64 = 3
+
+var downloadSemaphore = semaphore.NewWeighted(MaxParallelDownloads)
+
+// BatchDownloadRequest is the payload for the /download_assets endpoint: one
+// app downloading many assets at once, each running through the same engine
+// as the single-asset endpoint.
+type BatchDownloadRequest struct {
+	AppID  string         `json:"app_id"`
+	Assets []DownloadData `json:"assets"`
+}
+
+// batchProgress tracks the aggregate state of one batch so we can emit a
+// single summarizing TaskProgressUpdate alongside the per-asset ones.
+// BytesTotal/BytesDone are sized per-asset (not per-chunk), since nothing
+// below doAssetDownload reports live byte counts back out of the function.
+type batchProgress struct {
+	AppID      string
+	Total      int32
+	Completed  int32
+	BytesTotal int64
+	BytesDone  int64
+	cancel     context.CancelFunc
+}
+
+var (
+	BatchMux sync.Mutex
+	Batches  = make(map[string]*batchProgress)
+)
+
+func downloadAssetsHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading request body: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer r.Body.Close()
+
+	var batchReq BatchDownloadRequest
+	if err := json.Unmarshal(body, &batchReq); err != nil {
+		fmt.Println(">>> Error parsing BatchDownloadRequest:", err)
+		http.Error(w, "Error parsing JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var rJSON map[string]interface{}
+	if err := json.Unmarshal(body, &rJSON); err != nil {
+		fmt.Println(">>> Error parsing JSON:", err)
+		http.Error(w, "Error parsing JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	assetsJSON, _ := rJSON["assets"].([]interface{})
+
+	taskIDs, batchID := startBatchDownload(batchReq, assetsJSON)
+
+	resData := map[string]interface{}{"task_ids": taskIDs, "batch_id": batchID}
+	responseJSON, err := json.Marshal(resData)
+	if err != nil {
+		http.Error(w, "Error converting to JSON: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseJSON)
+}
+
+// startBatchDownload is the single download engine behind both the batch and
+// single-asset endpoints. It registers a batch_id and queues each asset
+// through doAssetDownload behind the shared downloadSemaphore, so a big batch
+// is throttled the same way a single busy download would be.
+func startBatchDownload(batchReq BatchDownloadRequest, assetsJSON []interface{}) ([]string, string) {
+	batchID := uuid.New().String()
+	batchCtx, cancel := context.WithCancel(context.Background())
+
+	batch := &batchProgress{
+		AppID:  batchReq.AppID,
+		Total:  int32(len(batchReq.Assets)),
+		cancel: cancel,
+	}
+	BatchMux.Lock()
+	Batches[batchID] = batch
+	BatchMux.Unlock()
+
+	taskIDs := make([]string, len(batchReq.Assets))
+	for i, asset := range batchReq.Assets {
+		taskID := uuid.New().String()
+		taskIDs[i] = taskID
+
+		var assetJSON map[string]interface{}
+		if i < len(assetsJSON) {
+			assetJSON, _ = assetsJSON[i].(map[string]interface{})
+		}
+
+		go runBatchedAssetDownload(batchCtx, batchID, assetJSON, asset, taskID)
+	}
+
+	return taskIDs, batchID
+}
+
+// resolveAssetSize best-effort resolves the byte size of an asset's download
+// so the batch can report bytes_total. A failure here (unresolvable URL, HEAD
+// request error) just leaves the asset's share of BytesTotal at 0 - it
+// doesn't block the download itself.
+func resolveAssetSize(ctx context.Context, data DownloadData) int64 {
+	canDownload, downloadURL, _, err := GetDownloadURL(data)
+	if err != nil || !canDownload {
+		return 0
+	}
+	_, size, err := checkRangeSupport(ctx, downloadURL)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// runBatchedAssetDownload waits for a free slot in downloadSemaphore, then
+// runs the existing single-asset download path unchanged. Sizing the asset
+// happens here, in its own goroutine, rather than in startBatchDownload's
+// loop - resolveAssetSize is a full API call plus a CDN HEAD, and the handler
+// that called startBatchDownload needs to return task_ids/batch_id without
+// waiting on N of those round-trips first.
+func runBatchedAssetDownload(batchCtx context.Context, batchID string, origJSON map[string]interface{}, data DownloadData, taskID string) {
+	assetSize := resolveAssetSize(batchCtx, data)
+	addBatchBytesTotal(batchID, assetSize)
+
+	if err := downloadSemaphore.Acquire(batchCtx, 1); err != nil {
+		TaskErrorCh <- &TaskError{AppID: data.AppID, TaskID: taskID, Error: err}
+		finishBatchAsset(batchID, assetSize)
+		return
+	}
+	defer downloadSemaphore.Release(1)
+
+	doAssetDownload(origJSON, data, taskID, batchCtx)
+	finishBatchAsset(batchID, assetSize)
+}
+
+// addBatchBytesTotal adds size to batchID's running BytesTotal, if the batch
+// is still tracked.
+func addBatchBytesTotal(batchID string, size int64) {
+	BatchMux.Lock()
+	batch, ok := Batches[batchID]
+	BatchMux.Unlock()
+	if ok {
+		atomic.AddInt64(&batch.BytesTotal, size)
+	}
+}
+
+// finishBatchAsset records one more completed asset (success or failure,
+// matching how Completed already counts both) and emits an aggregate
+// TaskProgressUpdate keyed by batchID, removing the batch once it's done.
+// assetSize is the byte size resolveAssetSize attributed to this asset at
+// queue time, added to BytesDone now that the asset has finished.
+func finishBatchAsset(batchID string, assetSize int64) {
+	BatchMux.Lock()
+	batch, ok := Batches[batchID]
+	BatchMux.Unlock()
+	if !ok {
+		return
+	}
+
+	completed := atomic.AddInt32(&batch.Completed, 1)
+	bytesDone := atomic.AddInt64(&batch.BytesDone, assetSize)
+	bytesTotal := atomic.LoadInt64(&batch.BytesTotal)
+	TaskProgressUpdateCh <- &TaskProgressUpdate{
+		AppID:    batch.AppID,
+		TaskID:   batchID,
+		Progress: int(100 * completed / batch.Total),
+		Message: fmt.Sprintf("Downloaded %d/%d assets (%.1f/%.1fMB)", completed, batch.Total,
+			float64(bytesDone)/1024/1024, float64(bytesTotal)/1024/1024),
+	}
+
+	if completed >= batch.Total {
+		BatchMux.Lock()
+		delete(Batches, batchID)
+		BatchMux.Unlock()
+	}
+}
+
+// cancelBatch cancels batchCtx, the parent of every task.Ctx doAssetDownload
+// creates for this batch (see doAssetDownload), so it stops both downloads
+// still queued behind downloadSemaphore and ones already running.
+func cancelBatch(batchID string) bool {
+	BatchMux.Lock()
+	batch, ok := Batches[batchID]
+	BatchMux.Unlock()
+	if !ok {
+		return false
+	}
+	batch.cancel()
+	return true
+}
+
+// cancelBatchHandler lets an add-on cancel a whole in-flight batch (e.g. the
+// user closes the asset browser mid-download) by posting the batch_id that
+// startBatchDownload returned, rather than cancelling each asset one by one.
+func cancelBatchHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading request body: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer r.Body.Close()
+
+	var req struct {
+		BatchID string `json:"batch_id"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "Error parsing JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cancelled := cancelBatch(req.BatchID)
+
+	responseJSON, err := json.Marshal(map[string]bool{"cancelled": cancelled})
+	if err != nil {
+		http.Error(w, "Error converting to JSON: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseJSON)
+}
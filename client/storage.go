@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Metadata is the subset of file metadata a StorageBackend can report without
+// the caller needing to know which backend is in play.
+type Metadata struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// StorageBackend abstracts where downloaded assets live. data.DownloadDirs
+// entries are backend URIs (a bare path or "file:///...", or "s3://bucket/prefix");
+// BackendForURI resolves each one to its implementation.
+type StorageBackend interface {
+	Exists(key string) (bool, int64, error)
+	Writer(key string) (io.WriteCloser, error)
+	Delete(key string) error
+	Stat(key string) (Metadata, error)
+	Join(parts ...string) string
+}
+
+// downloadTarget pairs a resolved backend with the key/path an asset should
+// be written to or read from within it.
+type downloadTarget struct {
+	Backend StorageBackend
+	Key     string
+}
+
+// BackendForURI resolves one data.DownloadDirs entry to its StorageBackend and
+// the backend-relative key to use as the root for that entry.
+func BackendForURI(uri string) (StorageBackend, string, error) {
+	if !strings.Contains(uri, "://") {
+		// Bare filesystem paths keep working for configs predating backend URIs.
+		return localFSBackend{}, uri, nil
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid download dir %q: %v", uri, err)
+	}
+
+	switch parsed.Scheme {
+	case "file":
+		return localFSBackend{}, parsed.Path, nil
+	case "s3":
+		backend, err := newS3Backend(parsed.Host)
+		if err != nil {
+			return nil, "", err
+		}
+		return backend, strings.TrimPrefix(parsed.Path, "/"), nil
+	default:
+		return nil, "", fmt.Errorf("unsupported download dir scheme %q", parsed.Scheme)
+	}
+}
+
+// localFSBackend implements StorageBackend on top of the local filesystem,
+// preserving the behaviour the client used before backends existed.
+type localFSBackend struct{}
+
+func (localFSBackend) Exists(key string) (bool, int64, error) {
+	info, err := os.Stat(key)
+	if os.IsNotExist(err) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, err
+	}
+	return true, info.Size(), nil
+}
+
+func (localFSBackend) Writer(key string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(key), os.ModePerm); err != nil {
+		return nil, err
+	}
+	return os.Create(key)
+}
+
+func (localFSBackend) Delete(key string) error {
+	return os.RemoveAll(key)
+}
+
+func (localFSBackend) Stat(key string) (Metadata, error) {
+	info, err := os.Stat(key)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (localFSBackend) Join(parts ...string) string {
+	return filepath.Join(parts...)
+}
+
+// s3Backend stores assets in an S3 bucket for studios sharing a download
+// cache across machines. Uploads go through the SDK's multipart manager so
+// large .blend files don't need to be buffered in memory.
+type s3Backend struct {
+	bucket   string
+	client   *s3.Client
+	uploader *manager.Uploader
+}
+
+func newS3Backend(bucket string) (*s3Backend, error) {
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for s3 backend: %v", err)
+	}
+	client := s3.NewFromConfig(cfg)
+	return &s3Backend{
+		bucket:   bucket,
+		client:   client,
+		uploader: manager.NewUploader(client),
+	}, nil
+}
+
+func (b *s3Backend) Exists(key string) (bool, int64, error) {
+	out, err := b.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return false, 0, nil
+		}
+		return false, 0, err
+	}
+	return true, aws.ToInt64(out.ContentLength), nil
+}
+
+// Writer streams into S3 through a pipe: writes into the returned
+// io.WriteCloser are relayed to a background multipart upload, and Close
+// blocks until that upload finishes (or failed).
+func (b *s3Backend) Writer(key string) (io.WriteCloser, error) {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.uploader.Upload(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String(b.bucket),
+			Key:    aws.String(key),
+			Body:   pr,
+		})
+		pr.CloseWithError(err)
+		done <- err
+	}()
+	return &s3Writer{pw: pw, done: done}, nil
+}
+
+type s3Writer struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) { return w.pw.Write(p) }
+
+func (w *s3Writer) Close() error {
+	if err := w.pw.Close(); err != nil {
+		return err
+	}
+	return <-w.done
+}
+
+func (b *s3Backend) Delete(key string) error {
+	_, err := b.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (b *s3Backend) Stat(key string) (Metadata, error) {
+	out, err := b.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{Size: aws.ToInt64(out.ContentLength), ModTime: aws.ToTime(out.LastModified)}, nil
+}
+
+// Join uses path.Join rather than filepath.Join, since S3 keys aren't
+// filesystem paths and shouldn't pick up backslashes on Windows clients.
+func (b *s3Backend) Join(parts ...string) string {
+	return path.Join(parts...)
+}
+
+func isS3NotFound(err error) bool {
+	return strings.Contains(err.Error(), "NotFound") || strings.Contains(err.Error(), "404")
+}